@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/juanjoss/spicedb-test/pkg/authzclient"
+	"github.com/spf13/cobra"
+)
+
+func newContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context",
+		Short: "Manage saved endpoint/token pairs",
+	}
+
+	cmd.AddCommand(newContextSetCmd())
+	cmd.AddCommand(newContextUseCmd())
+	cmd.AddCommand(newContextListCmd())
+
+	return cmd
+}
+
+func newContextSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <name> <endpoint> <token>",
+		Short: "Save an endpoint/token pair under a name",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := authzclient.LoadConfig()
+			if err != nil {
+				return err
+			}
+
+			if err := cfg.SetContext(args[0], args[1], args[2]); err != nil {
+				return err
+			}
+
+			fmt.Printf("context %q saved\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newContextUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use <name>",
+		Short: "Select the context used by default",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := authzclient.LoadConfig()
+			if err != nil {
+				return err
+			}
+
+			if err := cfg.UseContext(args[0]); err != nil {
+				return err
+			}
+
+			fmt.Printf("switched to context %q\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newContextListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List saved contexts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := authzclient.LoadConfig()
+			if err != nil {
+				return err
+			}
+
+			for name, zctx := range cfg.Contexts {
+				marker := " "
+				if name == cfg.CurrentContext {
+					marker = "*"
+				}
+				fmt.Printf("%s %s\t%s\n", marker, name, zctx.Endpoint)
+			}
+
+			return nil
+		},
+	}
+}
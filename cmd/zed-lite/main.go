@@ -0,0 +1,15 @@
+// Command zed-lite is a small zed-style CLI for scripting authorization
+// tests against a SpiceDB instance without editing Go source.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
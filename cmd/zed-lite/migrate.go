@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/juanjoss/spicedb-test/pkg/migrations"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply or roll back schema and relationship fixtures",
+	}
+
+	cmd.AddCommand(newMigrateApplyCmd())
+	cmd.AddCommand(newMigrateRollbackCmd())
+
+	return cmd
+}
+
+func newMigrateApplyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "apply <name> <dir>",
+		Short: "Apply the schema and fixtures in dir, recording it as name in the ledger",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			if err := migrations.Apply(context.Background(), client, os.DirFS(args[1]), args[0]); err != nil {
+				return fmt.Errorf("applying migration %q: %w", args[0], err)
+			}
+
+			fmt.Printf("migration %q applied\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newMigrateRollbackCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rollback <name>",
+		Short: "Roll back the fixtures that were applied as name, as recorded in the ledger",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			if err := migrations.Rollback(context.Background(), client, args[0]); err != nil {
+				return fmt.Errorf("rolling back migration %q: %w", args[0], err)
+			}
+
+			fmt.Printf("migration %q rolled back\n", args[0])
+			return nil
+		},
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pb "github.com/authzed/authzed-go/proto/authzed/api/v1"
+	"github.com/juanjoss/spicedb-test/pkg/authzclient"
+	"github.com/spf13/cobra"
+)
+
+func newPermissionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "permission",
+		Short: "Check permissions and report who can do what",
+	}
+
+	cmd.AddCommand(newPermissionCheckCmd())
+	cmd.AddCommand(newPermissionLookupResourcesCmd())
+	cmd.AddCommand(newPermissionLookupSubjectsCmd())
+
+	return cmd
+}
+
+// consistencyFlags holds the shared --at-least-as-fresh/--full-consistency/
+// --minimize-latency flags offered by every permission subcommand.
+type consistencyFlags struct {
+	zedToken        string
+	fullyConsistent bool
+	minimizeLatency bool
+}
+
+func (f *consistencyFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.zedToken, "at-least-as-fresh", "", "ZedToken from a prior write to guarantee this request observes it")
+	cmd.Flags().BoolVar(&f.fullyConsistent, "full-consistency", false, "evaluate against the latest data, ignoring cached snapshots")
+	cmd.Flags().BoolVar(&f.minimizeLatency, "minimize-latency", false, "evaluate against a recent snapshot, favoring low latency (default)")
+}
+
+func (f *consistencyFlags) resolve(client *authzclient.Client) *pb.Consistency {
+	switch {
+	case f.fullyConsistent:
+		return client.FullyConsistent()
+	case f.minimizeLatency:
+		return client.MinimizeLatency()
+	case f.zedToken != "":
+		return client.AtLeastAsFresh(&pb.ZedToken{Token: f.zedToken})
+	}
+
+	return nil
+}
+
+func newPermissionCheckCmd() *cobra.Command {
+	var (
+		flags       consistencyFlags
+		explain     bool
+		contextJSON string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "check <resourceType:id> <permission> <subjectType:id>",
+		Short: "Check whether a subject has a permission on a resource",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resourceType, resourceID, err := splitObjectRef(args[0])
+			if err != nil {
+				return err
+			}
+
+			subjectType, subjectID, err := splitObjectRef(args[2])
+			if err != nil {
+				return err
+			}
+
+			caveatContext, err := parseContext(contextJSON)
+			if err != nil {
+				return err
+			}
+
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			resource := &pb.ObjectReference{ObjectType: resourceType, ObjectId: resourceID}
+			subject := &pb.SubjectReference{Object: &pb.ObjectReference{ObjectType: subjectType, ObjectId: subjectID}}
+			consistency := flags.resolve(client)
+
+			resp, err := client.CheckPermission(context.Background(), subject, args[1], resource, consistency, caveatContext)
+			if err != nil {
+				return fmt.Errorf("checking permission: %w", err)
+			}
+
+			fmt.Println(resp.Permissionship)
+
+			if explain {
+				if err := printExplanation(client, resourceType, resource, args[1], consistency); err != nil {
+					return fmt.Errorf("explaining check: %w", err)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().BoolVar(&explain, "explain", false, "also print the relations the permission depends on and the resource's expanded permission tree (not narrowed to the checked subject)")
+	cmd.Flags().StringVar(&contextJSON, "caveat-context", "", `ABAC context as a JSON object, e.g. '{"now":"2024-01-01T10:00:00Z"}', for evaluating any caveats attached to contributing relationships`)
+
+	return cmd
+}
+
+// printExplanation prints the schema-level relations a permission depends
+// on, followed by the resource's expanded permission tree. The tree lists
+// every subject holding the permission on the resource; it is not narrowed
+// to the checked subject, and caveat context is not applied to it.
+func printExplanation(client *authzclient.Client, resourceType string, resource *pb.ObjectReference, permission string, consistency *pb.Consistency) error {
+	relations, err := client.DependentRelations(context.Background(), resourceType, permission)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s depends on: %s\n", permission, strings.Join(relations, ", "))
+
+	tree, err := client.ExpandPermissionTree(context.Background(), resource, permission, consistency)
+	if err != nil {
+		return err
+	}
+	fmt.Println("contributing edges:")
+	printTree(tree, 1)
+
+	return nil
+}
+
+func printTree(tree *pb.PermissionRelationshipTree, depth int) {
+	if tree == nil {
+		return
+	}
+
+	indent := strings.Repeat("  ", depth)
+
+	switch node := tree.TreeType.(type) {
+	case *pb.PermissionRelationshipTree_Leaf:
+		for _, subject := range node.Leaf.Subjects {
+			fmt.Printf("%s%s:%s\n", indent, subject.Object.ObjectType, subject.Object.ObjectId)
+		}
+	case *pb.PermissionRelationshipTree_Intermediate:
+		fmt.Printf("%s%s\n", indent, node.Intermediate.Operation)
+		for _, child := range node.Intermediate.Children {
+			printTree(child, depth+1)
+		}
+	}
+}
+
+func newPermissionLookupResourcesCmd() *cobra.Command {
+	var flags consistencyFlags
+
+	cmd := &cobra.Command{
+		Use:   "lookup-resources <resourceType> <permission> <subjectType:id>",
+		Short: "List every resource of a type that a subject has a permission on",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			subjectType, subjectID, err := splitObjectRef(args[2])
+			if err != nil {
+				return err
+			}
+
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			subject := &pb.SubjectReference{Object: &pb.ObjectReference{ObjectType: subjectType, ObjectId: subjectID}}
+
+			results, err := client.LookupResources(context.Background(), subject, args[1], args[0], flags.resolve(client))
+			if err != nil {
+				return fmt.Errorf("looking up resources: %w", err)
+			}
+
+			for _, res := range results {
+				fmt.Printf("%s:%s\t%s\n", args[0], res.ResourceObjectId, res.Permissionship)
+			}
+
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+
+	return cmd
+}
+
+func newPermissionLookupSubjectsCmd() *cobra.Command {
+	var flags consistencyFlags
+
+	cmd := &cobra.Command{
+		Use:   "lookup-subjects <resourceType:id> <permission> <subjectType>",
+		Short: "List every subject of a type that has a permission on a resource",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resourceType, resourceID, err := splitObjectRef(args[0])
+			if err != nil {
+				return err
+			}
+
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			resource := &pb.ObjectReference{ObjectType: resourceType, ObjectId: resourceID}
+
+			results, err := client.LookupSubjects(context.Background(), resource, args[1], args[2], flags.resolve(client))
+			if err != nil {
+				return fmt.Errorf("looking up subjects: %w", err)
+			}
+
+			for _, res := range results {
+				fmt.Printf("%s:%s\t%s\n", args[2], res.Subject.SubjectObjectId, res.Subject.Permissionship)
+			}
+
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+
+	return cmd
+}
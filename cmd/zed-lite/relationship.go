@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/authzed/authzed-go/proto/authzed/api/v1"
+	"github.com/spf13/cobra"
+)
+
+func newRelationshipCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "relationship",
+		Short: "Touch, delete, or read relationships",
+	}
+
+	cmd.AddCommand(newRelationshipTouchCmd())
+	cmd.AddCommand(newRelationshipDeleteCmd())
+	cmd.AddCommand(newRelationshipReadCmd())
+
+	return cmd
+}
+
+func newRelationshipTouchCmd() *cobra.Command {
+	var (
+		caveatName  string
+		contextJSON string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "touch <resource:id> <relation> <subjectType:id>",
+		Short: "Upsert a relationship",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resourceType, resourceID, err := splitObjectRef(args[0])
+			if err != nil {
+				return err
+			}
+
+			subjectType, subjectID, err := splitObjectRef(args[2])
+			if err != nil {
+				return err
+			}
+
+			caveatContext, err := parseContext(contextJSON)
+			if err != nil {
+				return err
+			}
+			if caveatContext != nil && caveatName == "" {
+				return fmt.Errorf("--caveat-context requires --caveat")
+			}
+
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			var resp *pb.WriteRelationshipsResponse
+			if caveatName != "" {
+				resp, err = client.TouchCaveatedRelationship(context.Background(), resourceType, resourceID, args[1], subjectType, subjectID, caveatName, caveatContext)
+			} else {
+				resp, err = client.TouchRelationship(context.Background(), resourceType, resourceID, args[1], subjectType, subjectID)
+			}
+			if err != nil {
+				return fmt.Errorf("touching relationship: %w", err)
+			}
+
+			fmt.Printf("relationship touched, zedtoken: %s\n", resp.WrittenAt.Token)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&caveatName, "caveat", "", "name of a caveat declared in the schema to attach to this relationship")
+	cmd.Flags().StringVar(&contextJSON, "caveat-context", "", `default ABAC context for the caveat, as a JSON object, e.g. '{"open_hour":9}'`)
+
+	return cmd
+}
+
+func newRelationshipDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <resource:id> <relation> <subjectType:id>",
+		Short: "Delete a relationship",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resourceType, resourceID, err := splitObjectRef(args[0])
+			if err != nil {
+				return err
+			}
+
+			subjectType, subjectID, err := splitObjectRef(args[2])
+			if err != nil {
+				return err
+			}
+
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			resp, err := client.DeleteRelationship(context.Background(), resourceType, resourceID, args[1], subjectType, subjectID)
+			if err != nil {
+				return fmt.Errorf("deleting relationship: %w", err)
+			}
+
+			fmt.Printf("relationship deleted, zedtoken: %s\n", resp.WrittenAt.Token)
+			return nil
+		},
+	}
+}
+
+func newRelationshipReadCmd() *cobra.Command {
+	var relation string
+
+	cmd := &cobra.Command{
+		Use:   "read <resourceType:id>",
+		Short: "List relationships for a resource",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resourceType, resourceID, err := splitObjectRef(args[0])
+			if err != nil {
+				return err
+			}
+
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			rels, err := client.ReadRelationships(context.Background(), resourceType, resourceID, relation)
+			if err != nil {
+				return fmt.Errorf("reading relationships: %w", err)
+			}
+
+			for _, rel := range rels {
+				fmt.Printf("%s:%s#%s@%s:%s\n",
+					rel.Resource.ObjectType, rel.Resource.ObjectId, rel.Relation,
+					rel.Subject.Object.ObjectType, rel.Subject.Object.ObjectId)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&relation, "relation", "", "only list relationships with this relation")
+
+	return cmd
+}
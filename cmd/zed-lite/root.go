@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/juanjoss/spicedb-test/pkg/authzclient"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagContext  string
+	flagEndpoint string
+	flagToken    string
+)
+
+func newRootCmd() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "zed-lite",
+		Short: "A small zed-style CLI for scripting SpiceDB authorization checks",
+	}
+
+	root.PersistentFlags().StringVar(&flagContext, "context", "", "named context to use (defaults to the current context)")
+	root.PersistentFlags().StringVar(&flagEndpoint, "endpoint", "", "SpiceDB endpoint (overrides the selected context)")
+	root.PersistentFlags().StringVar(&flagToken, "token", "", "SpiceDB preshared key (overrides the selected context)")
+
+	root.AddCommand(newSchemaCmd())
+	root.AddCommand(newRelationshipCmd())
+	root.AddCommand(newPermissionCmd())
+	root.AddCommand(newContextCmd())
+	root.AddCommand(newMigrateCmd())
+
+	return root
+}
+
+// newClient resolves the endpoint/token for the current invocation, giving
+// precedence to --endpoint/--token over the selected context, and dials
+// SpiceDB.
+func newClient() (*authzclient.Client, error) {
+	endpoint, token := flagEndpoint, flagToken
+
+	if endpoint == "" || token == "" {
+		cfg, err := authzclient.LoadConfig()
+		if err != nil {
+			return nil, fmt.Errorf("loading config: %w", err)
+		}
+
+		ctxName := flagContext
+		if ctxName == "" {
+			ctxName = cfg.CurrentContext
+		}
+
+		zctx, ok := cfg.Contexts[ctxName]
+		if !ok {
+			return nil, fmt.Errorf("no context named %q; set one with `zed-lite context set`", ctxName)
+		}
+
+		if endpoint == "" {
+			endpoint = zctx.Endpoint
+		}
+		if token == "" {
+			token = zctx.Token
+		}
+	}
+
+	return authzclient.New(endpoint, token)
+}
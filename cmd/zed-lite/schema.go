@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newSchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Read or write the SpiceDB schema",
+	}
+
+	cmd.AddCommand(newSchemaWriteCmd())
+	cmd.AddCommand(newSchemaReadCmd())
+	cmd.AddCommand(newSchemaDepsCmd())
+
+	return cmd
+}
+
+func newSchemaWriteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "write <schema-file>",
+		Short: "Write a .zed schema file to SpiceDB",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", args[0], err)
+			}
+
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			if _, err := client.WriteSchema(context.Background(), string(data)); err != nil {
+				return fmt.Errorf("writing schema: %w", err)
+			}
+
+			fmt.Println("schema written")
+			return nil
+		},
+	}
+}
+
+func newSchemaReadCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "read",
+		Short: "Print the schema currently deployed to SpiceDB",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			resp, err := client.ReadSchema(context.Background())
+			if err != nil {
+				return fmt.Errorf("reading schema: %w", err)
+			}
+
+			fmt.Println(resp.SchemaText)
+			return nil
+		},
+	}
+}
+
+func newSchemaDepsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "deps <resourceType> <permission>",
+		Short: "Print the relations a permission transitively depends on",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			relations, err := client.DependentRelations(context.Background(), args[0], args[1])
+			if err != nil {
+				return fmt.Errorf("resolving dependent relations: %w", err)
+			}
+
+			fmt.Printf("%s depends on: %s\n", args[1], strings.Join(relations, ", "))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// splitObjectRef splits a "type:id" reference as used on the command line,
+// e.g. "namespace/payment:payment_1".
+func splitObjectRef(ref string) (objType, objID string, err error) {
+	parts := strings.SplitN(ref, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid object reference %q, expected type:id", ref)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// parseContext turns a JSON object string, e.g. `{"now": "2024-01-01T10:00:00Z"}`,
+// into the structpb.Struct caveats are evaluated against. An empty string
+// returns a nil Struct, meaning no caveat context is supplied.
+func parseContext(raw string) (*structpb.Struct, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return nil, fmt.Errorf("invalid context JSON: %w", err)
+	}
+
+	return structpb.NewStruct(fields)
+}
@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+func TestSplitObjectRef(t *testing.T) {
+	gotType, gotID, err := splitObjectRef("namespace/payment:payment_1")
+	if err != nil {
+		t.Fatalf("splitObjectRef: %v", err)
+	}
+	if gotType != "namespace/payment" || gotID != "payment_1" {
+		t.Fatalf("splitObjectRef = (%q, %q), want (%q, %q)", gotType, gotID, "namespace/payment", "payment_1")
+	}
+}
+
+func TestSplitObjectRefErrors(t *testing.T) {
+	cases := []string{
+		"namespace/payment",
+		"namespace/payment:",
+		":payment_1",
+		"",
+	}
+
+	for _, ref := range cases {
+		if _, _, err := splitObjectRef(ref); err == nil {
+			t.Errorf("splitObjectRef(%q): want error, got nil", ref)
+		}
+	}
+}
+
+func TestParseContextEmpty(t *testing.T) {
+	ctx, err := parseContext("")
+	if err != nil {
+		t.Fatalf("parseContext: %v", err)
+	}
+	if ctx != nil {
+		t.Fatalf("parseContext(\"\") = %v, want nil", ctx)
+	}
+}
+
+func TestParseContextValid(t *testing.T) {
+	ctx, err := parseContext(`{"now":"2024-01-01T10:00:00Z"}`)
+	if err != nil {
+		t.Fatalf("parseContext: %v", err)
+	}
+
+	got, ok := ctx.Fields["now"]
+	if !ok {
+		t.Fatalf("parseContext result missing %q field: %v", "now", ctx)
+	}
+	if got.GetStringValue() != "2024-01-01T10:00:00Z" {
+		t.Fatalf("parseContext[\"now\"] = %q, want %q", got.GetStringValue(), "2024-01-01T10:00:00Z")
+	}
+}
+
+func TestParseContextInvalidJSON(t *testing.T) {
+	if _, err := parseContext("not json"); err == nil {
+		t.Fatal("parseContext(\"not json\"): want error, got nil")
+	}
+}
@@ -0,0 +1,253 @@
+// Package authzclient wraps an authzed-go client with the request/response
+// plumbing this repo's examples and CLI need, so callers don't have to
+// build pb types by hand for every call.
+package authzclient
+
+import (
+	"context"
+	"io"
+
+	pb "github.com/authzed/authzed-go/proto/authzed/api/v1"
+	"github.com/authzed/authzed-go/v1"
+	"github.com/authzed/grpcutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Client wraps *authzed.Client with the higher-level operations used
+// throughout this repo's examples and the zed-lite CLI.
+type Client struct {
+	*authzed.Client
+}
+
+// New dials the SpiceDB instance at endpoint and authenticates with token.
+// Extra grpc.DialOption values are appended after the defaults, so callers
+// can override transport credentials (e.g. for TLS endpoints).
+func New(endpoint, token string, opts ...grpc.DialOption) (*Client, error) {
+	dialOpts := append([]grpc.DialOption{
+		grpcutil.WithInsecureBearerToken(token),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)
+
+	c, err := authzed.NewClient(endpoint, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{Client: c}, nil
+}
+
+// WriteSchema replaces the active schema with the given SpiceDB schema
+// language source.
+func (c *Client) WriteSchema(ctx context.Context, schema string) (*pb.WriteSchemaResponse, error) {
+	return c.Client.WriteSchema(ctx, &pb.WriteSchemaRequest{Schema: schema})
+}
+
+// ReadSchema returns the schema currently deployed to SpiceDB.
+func (c *Client) ReadSchema(ctx context.Context) (*pb.ReadSchemaResponse, error) {
+	return c.Client.ReadSchema(ctx, &pb.ReadSchemaRequest{})
+}
+
+// WriteRelationships applies the given relationship updates as a single
+// transaction and returns the response from SpiceDB.
+func (c *Client) WriteRelationships(ctx context.Context, updates []*pb.RelationshipUpdate) (*pb.WriteRelationshipsResponse, error) {
+	return c.Client.WriteRelationships(ctx, &pb.WriteRelationshipsRequest{Updates: updates})
+}
+
+// TouchRelationship is a convenience wrapper around WriteRelationships for
+// the common case of upserting a single relationship.
+func (c *Client) TouchRelationship(ctx context.Context, resourceType, resourceID, relation, subjectType, subjectID string) (*pb.WriteRelationshipsResponse, error) {
+	return c.WriteRelationships(ctx, []*pb.RelationshipUpdate{
+		relationshipUpdate(pb.RelationshipUpdate_OPERATION_TOUCH, resourceType, resourceID, relation, subjectType, subjectID),
+	})
+}
+
+// TouchCaveatedRelationship is like TouchRelationship, but attaches the
+// named caveat to the relationship. The caveat must already be declared in
+// the deployed schema; caveatContext supplies the values its expression
+// needs that aren't already present on the Check's own context, e.g. a
+// fixed limit the caveat's expression compares against.
+func (c *Client) TouchCaveatedRelationship(ctx context.Context, resourceType, resourceID, relation, subjectType, subjectID, caveatName string, caveatContext *structpb.Struct) (*pb.WriteRelationshipsResponse, error) {
+	update := relationshipUpdate(pb.RelationshipUpdate_OPERATION_TOUCH, resourceType, resourceID, relation, subjectType, subjectID)
+	update.Relationship.OptionalCaveat = &pb.ContextualizedCaveat{
+		CaveatName: caveatName,
+		Context:    caveatContext,
+	}
+
+	return c.WriteRelationships(ctx, []*pb.RelationshipUpdate{update})
+}
+
+// DeleteRelationship removes a single relationship, if it exists.
+func (c *Client) DeleteRelationship(ctx context.Context, resourceType, resourceID, relation, subjectType, subjectID string) (*pb.WriteRelationshipsResponse, error) {
+	return c.WriteRelationships(ctx, []*pb.RelationshipUpdate{
+		relationshipUpdate(pb.RelationshipUpdate_OPERATION_DELETE, resourceType, resourceID, relation, subjectType, subjectID),
+	})
+}
+
+// ReadRelationships streams every relationship matching the given resource
+// filter and returns them as a slice.
+func (c *Client) ReadRelationships(ctx context.Context, resourceType, resourceID, relation string) ([]*pb.Relationship, error) {
+	stream, err := c.Client.ReadRelationships(ctx, &pb.ReadRelationshipsRequest{
+		RelationshipFilter: &pb.RelationshipFilter{
+			ResourceType:       resourceType,
+			OptionalResourceId: resourceID,
+			OptionalRelation:   relation,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rels []*pb.Relationship
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		rels = append(rels, resp.Relationship)
+	}
+
+	return rels, nil
+}
+
+// CheckPermission asks SpiceDB whether subject has permission on resource.
+// A nil consistency lets the server pick its default (minimize-latency),
+// which is fine for most reads but can race a recent write; use
+// AtLeastAsFresh with the ZedToken from a prior write to pin the read to
+// see it. caveatContext supplies the ABAC values any caveats attached to
+// the contributing relationships need to evaluate, e.g. {"now": ...} for a
+// within_business_hours caveat; it may be nil if none apply.
+func (c *Client) CheckPermission(ctx context.Context, subject *pb.SubjectReference, permission string, resource *pb.ObjectReference, consistency *pb.Consistency, caveatContext *structpb.Struct) (*pb.CheckPermissionResponse, error) {
+	return c.Client.CheckPermission(ctx, &pb.CheckPermissionRequest{
+		Resource:    resource,
+		Permission:  permission,
+		Subject:     subject,
+		Consistency: consistency,
+		Context:     caveatContext,
+	})
+}
+
+// FullyConsistent requires the request to be evaluated against the latest
+// data, at the cost of the higher latency of a fresh read.
+func (c *Client) FullyConsistent() *pb.Consistency {
+	return &pb.Consistency{Requirement: &pb.Consistency_FullyConsistent{FullyConsistent: true}}
+}
+
+// MinimizeLatency allows the request to be evaluated against a recent,
+// possibly stale, snapshot in exchange for the lowest latency. This is the
+// server's default when no consistency is specified.
+func (c *Client) MinimizeLatency() *pb.Consistency {
+	return &pb.Consistency{Requirement: &pb.Consistency_MinimizeLatency{MinimizeLatency: true}}
+}
+
+// AtLeastAsFresh requires the request to be evaluated against a snapshot at
+// least as fresh as the given ZedToken. Pass the ZedToken returned from a
+// prior write to guarantee the read observes it (read-your-writes).
+func (c *Client) AtLeastAsFresh(zedToken *pb.ZedToken) *pb.Consistency {
+	return &pb.Consistency{Requirement: &pb.Consistency_AtLeastAsFresh{AtLeastAsFresh: zedToken}}
+}
+
+// AtExactSnapshot pins the request to the exact snapshot identified by the
+// given ZedToken, rather than any snapshot at least as fresh.
+func (c *Client) AtExactSnapshot(zedToken *pb.ZedToken) *pb.Consistency {
+	return &pb.Consistency{Requirement: &pb.Consistency_AtExactSnapshot{AtExactSnapshot: zedToken}}
+}
+
+// LookupResources returns every object of resourceType that subject has
+// permission on, streaming the full result set into a slice. Prefer this
+// over issuing a CheckPermission per candidate resource, which is O(N*M)
+// and doesn't scale.
+func (c *Client) LookupResources(ctx context.Context, subject *pb.SubjectReference, permission, resourceType string, consistency *pb.Consistency) ([]*pb.LookupResourcesResponse, error) {
+	stream, err := c.Client.LookupResources(ctx, &pb.LookupResourcesRequest{
+		ResourceObjectType: resourceType,
+		Permission:         permission,
+		Subject:            subject,
+		Consistency:        consistency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*pb.LookupResourcesResponse
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		results = append(results, resp)
+	}
+
+	return results, nil
+}
+
+// LookupSubjects returns every subject of subjectType that has permission
+// on resource, streaming the full result set into a slice.
+func (c *Client) LookupSubjects(ctx context.Context, resource *pb.ObjectReference, permission, subjectType string, consistency *pb.Consistency) ([]*pb.LookupSubjectsResponse, error) {
+	stream, err := c.Client.LookupSubjects(ctx, &pb.LookupSubjectsRequest{
+		Resource:          resource,
+		Permission:        permission,
+		SubjectObjectType: subjectType,
+		Consistency:       consistency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*pb.LookupSubjectsResponse
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		results = append(results, resp)
+	}
+
+	return results, nil
+}
+
+// ExpandPermissionTree returns the tree of relations and subjects that
+// actually contributed to permission on resource, which is the data-level
+// counterpart to DependentRelations' schema-level view: DependentRelations
+// says which relations *can* feed a permission, this says which ones *did*
+// for this particular object.
+func (c *Client) ExpandPermissionTree(ctx context.Context, resource *pb.ObjectReference, permission string, consistency *pb.Consistency) (*pb.PermissionRelationshipTree, error) {
+	resp, err := c.Client.ExpandPermissionTree(ctx, &pb.ExpandPermissionTreeRequest{
+		Resource:    resource,
+		Permission:  permission,
+		Consistency: consistency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.TreeRoot, nil
+}
+
+func relationshipUpdate(op pb.RelationshipUpdate_Operation, resourceType, resourceID, relation, subjectType, subjectID string) *pb.RelationshipUpdate {
+	return &pb.RelationshipUpdate{
+		Operation: op,
+		Relationship: &pb.Relationship{
+			Resource: &pb.ObjectReference{
+				ObjectType: resourceType,
+				ObjectId:   resourceID,
+			},
+			Relation: relation,
+			Subject: &pb.SubjectReference{
+				Object: &pb.ObjectReference{
+					ObjectType: subjectType,
+					ObjectId:   subjectID,
+				},
+			},
+		},
+	}
+}
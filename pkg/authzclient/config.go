@@ -0,0 +1,125 @@
+package authzclient
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Context holds the endpoint/token pair needed to dial a SpiceDB instance.
+type Context struct {
+	Endpoint string `yaml:"endpoint"`
+	Token    string `yaml:"token"`
+}
+
+// Config is the on-disk, zed-style context store: a set of named
+// endpoint/token pairs plus which one is active.
+type Config struct {
+	CurrentContext string             `yaml:"current-context"`
+	Contexts       map[string]Context `yaml:"contexts"`
+}
+
+// configPath returns the location of the config file under
+// $XDG_CONFIG_HOME, falling back to ~/.config when unset.
+func configPath() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(configHome, "zed-lite", "config.yaml"), nil
+}
+
+// LoadConfig reads the zed-lite config file, returning an empty Config if
+// it doesn't exist yet.
+func LoadConfig() (*Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Config{Contexts: map[string]Context{}}, nil
+		}
+		return nil, err
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Contexts == nil {
+		cfg.Contexts = map[string]Context{}
+	}
+
+	return &cfg, nil
+}
+
+// Save writes the config back to $XDG_CONFIG_HOME/zed-lite/config.yaml,
+// creating the parent directory if needed.
+func (c *Config) Save() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// SetContext upserts a named context and saves the config.
+func (c *Config) SetContext(name, endpoint, token string) error {
+	c.Contexts[name] = Context{Endpoint: endpoint, Token: token}
+	return c.Save()
+}
+
+// UseContext marks name as the current context and saves the config. It
+// returns an error if no such context has been set.
+func (c *Config) UseContext(name string) error {
+	if _, ok := c.Contexts[name]; !ok {
+		return &ContextNotFoundError{Name: name}
+	}
+
+	c.CurrentContext = name
+	return c.Save()
+}
+
+// Current returns the active context, or an error if none is selected.
+func (c *Config) Current() (Context, error) {
+	if c.CurrentContext == "" {
+		return Context{}, &ContextNotFoundError{Name: "(none selected)"}
+	}
+
+	ctx, ok := c.Contexts[c.CurrentContext]
+	if !ok {
+		return Context{}, &ContextNotFoundError{Name: c.CurrentContext}
+	}
+
+	return ctx, nil
+}
+
+// ContextNotFoundError is returned when a named context doesn't exist in
+// the config file.
+type ContextNotFoundError struct {
+	Name string
+}
+
+func (e *ContextNotFoundError) Error() string {
+	return "no such context: " + e.Name
+}
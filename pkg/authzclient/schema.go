@@ -0,0 +1,32 @@
+package authzclient
+
+import (
+	"context"
+	"sort"
+
+	pb "github.com/authzed/authzed-go/proto/authzed/api/v1"
+)
+
+// DependentRelations returns the transitive set of relations that feed the
+// named permission on definition, e.g. DependentRelations(ctx, "namespace/
+// payment", "view") might return ["collector", "marketplace_owner",
+// "payer"]. It's backed by SpiceDB's schema reflection RPC rather than
+// parsing the schema client-side, so it reflects exactly what the server
+// would use to evaluate the permission.
+func (c *Client) DependentRelations(ctx context.Context, definition, permission string) ([]string, error) {
+	resp, err := c.Client.ExperimentalDependentRelations(ctx, &pb.ExperimentalDependentRelationsRequest{
+		DefinitionName: definition,
+		PermissionName: permission,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	relations := make([]string, 0, len(resp.Relations))
+	for _, rel := range resp.Relations {
+		relations = append(relations, rel.RelationName)
+	}
+	sort.Strings(relations)
+
+	return relations, nil
+}
@@ -0,0 +1,418 @@
+// Package migrations applies versioned schema and relationship fixtures to
+// SpiceDB from an embedded filesystem, recording each applied migration as
+// a relationship so re-runs are safe.
+//
+//	//go:embed fixtures
+//	var fixtures embed.FS
+//
+//	err := migrations.Apply(ctx, client, fixtures, "001_initial")
+package migrations
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	pb "github.com/authzed/authzed-go/proto/authzed/api/v1"
+	"github.com/juanjoss/spicedb-test/pkg/authzclient"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+)
+
+// ledgerResourceType is the object type used to record which migrations
+// have already been applied, so a re-run is a no-op.
+const ledgerResourceType = "system/migration"
+
+// Fixture describes a single relationship to be written as part of a
+// migration's YAML fixture file.
+type Fixture struct {
+	Resource   string `yaml:"resource"`
+	ResourceID string `yaml:"resource_id"`
+	Relation   string `yaml:"relation"`
+	Subject    string `yaml:"subject"`
+	SubjectID  string `yaml:"subject_id"`
+}
+
+type fixtureFile struct {
+	Relationships []Fixture `yaml:"relationships"`
+}
+
+// Apply loads every *.zed schema file and *.yaml relationship fixture from
+// fsys, then, if the migration named name hasn't already been recorded in
+// the ledger, merges the schema on top of what's currently deployed and
+// writes the result with WriteSchema, then writes the fixtures together
+// with the ledger entries in a single WriteRelationships transaction. The
+// schema and relationship writes are separate calls: if the relationship
+// write fails, the merged schema stays written and the migration is not
+// recorded as applied.
+//
+// Apply is idempotent: calling it again with the same name is a no-op once
+// the migration has succeeded.
+func Apply(ctx context.Context, client *authzclient.Client, fsys fs.FS, name string) error {
+	applied, err := isApplied(ctx, client, name)
+	if err != nil {
+		return fmt.Errorf("checking migration ledger: %w", err)
+	}
+	if applied {
+		return nil
+	}
+
+	schema, err := loadSchema(fsys)
+	if err != nil {
+		return fmt.Errorf("loading schema: %w", err)
+	}
+
+	fixtures, err := loadFixtures(fsys)
+	if err != nil {
+		return fmt.Errorf("loading fixtures: %w", err)
+	}
+
+	if schema != "" {
+		deployed, err := readDeployedSchema(ctx, client)
+		if err != nil {
+			return fmt.Errorf("reading deployed schema: %w", err)
+		}
+
+		if _, err := client.WriteSchema(ctx, mergeSchemas(deployed, schema)); err != nil {
+			return fmt.Errorf("writing schema: %w", err)
+		}
+	}
+
+	updates := make([]*pb.RelationshipUpdate, 0, len(fixtures)*2+1)
+	for _, f := range fixtures {
+		updates = append(updates, touchUpdate(f))
+		updates = append(updates, ledgerFixtureUpdate(pb.RelationshipUpdate_OPERATION_TOUCH, name, f))
+	}
+	updates = append(updates, ledgerUpdate(name))
+
+	if _, err := client.WriteRelationships(ctx, updates); err != nil {
+		return fmt.Errorf("applying migration %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// Rollback deletes the relationships a prior Apply of name actually wrote,
+// recovered from the ledger rather than re-read from the migration's
+// source files, along with the ledger entries themselves, so the
+// migration can be safely re-applied even if its fixture files have since
+// been edited.
+func Rollback(ctx context.Context, client *authzclient.Client, name string) error {
+	fixtures, err := ledgerFixtures(ctx, client, name)
+	if err != nil {
+		return fmt.Errorf("reading migration ledger: %w", err)
+	}
+
+	updates := make([]*pb.RelationshipUpdate, 0, len(fixtures)*2+1)
+	for _, f := range fixtures {
+		updates = append(updates, deleteUpdate(f))
+		updates = append(updates, ledgerFixtureUpdate(pb.RelationshipUpdate_OPERATION_DELETE, name, f))
+	}
+	updates = append(updates, &pb.RelationshipUpdate{
+		Operation:    pb.RelationshipUpdate_OPERATION_DELETE,
+		Relationship: ledgerRelationship(name),
+	})
+
+	if _, err := client.WriteRelationships(ctx, updates); err != nil {
+		return fmt.Errorf("rolling back migration %q: %w", name, err)
+	}
+
+	return nil
+}
+
+func isApplied(ctx context.Context, client *authzclient.Client, name string) (bool, error) {
+	rels, err := client.ReadRelationships(ctx, ledgerResourceType, name, "applied")
+	if err != nil {
+		return false, err
+	}
+
+	return len(rels) > 0, nil
+}
+
+// ledgerFixtures recovers the exact fixtures a prior Apply of name wrote,
+// from the per-fixture ledger entries rather than the migration's (maybe
+// since-edited) source files.
+func ledgerFixtures(ctx context.Context, client *authzclient.Client, name string) ([]Fixture, error) {
+	rels, err := client.ReadRelationships(ctx, ledgerResourceType, name, "applied-fixture")
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures := make([]Fixture, 0, len(rels))
+	for _, rel := range rels {
+		f, err := decodeFixture(rel.Subject.Object.ObjectId)
+		if err != nil {
+			return nil, fmt.Errorf("decoding ledger entry: %w", err)
+		}
+		fixtures = append(fixtures, f)
+	}
+
+	return fixtures, nil
+}
+
+// readDeployedSchema returns the schema currently written to SpiceDB, or
+// "" if none has been written yet.
+func readDeployedSchema(ctx context.Context, client *authzclient.Client) (string, error) {
+	resp, err := client.ReadSchema(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return resp.SchemaText, nil
+}
+
+// mergeSchemas unions the top-level `definition`/`caveat` blocks of
+// incoming on top of deployed: a block in incoming replaces a block of the
+// same name in deployed (so a later migration can extend or redeclare an
+// earlier one), while every other block in deployed is preserved. This is
+// what makes WriteSchema - which always replaces the whole schema document
+// - safe to call from an incremental migration.
+func mergeSchemas(deployed, incoming string) string {
+	var order []string
+	blocks := map[string]string{}
+
+	for _, b := range splitSchemaBlocks(deployed) {
+		h := blockHeader(b)
+		if _, ok := blocks[h]; !ok {
+			order = append(order, h)
+		}
+		blocks[h] = b
+	}
+	for _, b := range splitSchemaBlocks(incoming) {
+		h := blockHeader(b)
+		if _, ok := blocks[h]; !ok {
+			order = append(order, h)
+		}
+		blocks[h] = b
+	}
+
+	var sb strings.Builder
+	for _, h := range order {
+		sb.WriteString(blocks[h])
+		sb.WriteString("\n\n")
+	}
+
+	return sb.String()
+}
+
+// splitSchemaBlocks splits schema source into its top-level `definition`
+// and `caveat` blocks, tracking brace depth so a block's own braces don't
+// confuse the split.
+func splitSchemaBlocks(schema string) []string {
+	var blocks []string
+	depth := 0
+	blockStart := -1
+
+	for i, r := range schema {
+		if depth == 0 && blockStart == -1 {
+			if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+				continue
+			}
+			blockStart = i
+		}
+
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 && blockStart != -1 {
+				blocks = append(blocks, schema[blockStart:i+1])
+				blockStart = -1
+			}
+		}
+	}
+
+	return blocks
+}
+
+// blockHeader returns the declaration portion of a schema block, e.g.
+// "definition namespace/payment" for a block starting with "definition
+// namespace/payment {", which is used as that block's identity when
+// merging. A caveat's parameter list is not part of its identity, so
+// "caveat within_business_hours(now timestamp) {" yields "caveat
+// within_business_hours".
+func blockHeader(block string) string {
+	header := block
+	if idx := strings.Index(header, "{"); idx != -1 {
+		header = header[:idx]
+	}
+	if idx := strings.Index(header, "("); idx != -1 {
+		header = header[:idx]
+	}
+	return strings.TrimSpace(header)
+}
+
+// loadSchema concatenates every *.zed file in fsys, in sorted path order,
+// so the combined schema is deterministic regardless of filesystem
+// iteration order.
+func loadSchema(fsys fs.FS) (string, error) {
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && path.Ext(p) == ".zed" {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(paths)
+
+	var sb strings.Builder
+	for _, p := range paths {
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return "", err
+		}
+		sb.Write(data)
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
+
+// loadFixtures parses every *.yaml relationship fixture file in fsys, in
+// sorted path order.
+func loadFixtures(fsys fs.FS) ([]Fixture, error) {
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && (path.Ext(p) == ".yaml" || path.Ext(p) == ".yml") {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var fixtures []Fixture
+	for _, p := range paths {
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, err
+		}
+
+		var ff fixtureFile
+		if err := yaml.Unmarshal(data, &ff); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", p, err)
+		}
+
+		fixtures = append(fixtures, ff.Relationships...)
+	}
+
+	return fixtures, nil
+}
+
+func touchUpdate(f Fixture) *pb.RelationshipUpdate {
+	return &pb.RelationshipUpdate{
+		Operation:    pb.RelationshipUpdate_OPERATION_TOUCH,
+		Relationship: fixtureRelationship(f),
+	}
+}
+
+func deleteUpdate(f Fixture) *pb.RelationshipUpdate {
+	return &pb.RelationshipUpdate{
+		Operation:    pb.RelationshipUpdate_OPERATION_DELETE,
+		Relationship: fixtureRelationship(f),
+	}
+}
+
+func fixtureRelationship(f Fixture) *pb.Relationship {
+	return &pb.Relationship{
+		Resource: &pb.ObjectReference{
+			ObjectType: f.Resource,
+			ObjectId:   f.ResourceID,
+		},
+		Relation: f.Relation,
+		Subject: &pb.SubjectReference{
+			Object: &pb.ObjectReference{
+				ObjectType: f.Subject,
+				ObjectId:   f.SubjectID,
+			},
+		},
+	}
+}
+
+func ledgerUpdate(name string) *pb.RelationshipUpdate {
+	return &pb.RelationshipUpdate{
+		Operation:    pb.RelationshipUpdate_OPERATION_TOUCH,
+		Relationship: ledgerRelationship(name),
+	}
+}
+
+func ledgerRelationship(name string) *pb.Relationship {
+	return &pb.Relationship{
+		Resource: &pb.ObjectReference{
+			ObjectType: ledgerResourceType,
+			ObjectId:   name,
+		},
+		Relation: "applied",
+		Subject: &pb.SubjectReference{
+			Object: &pb.ObjectReference{
+				ObjectType: ledgerResourceType,
+				ObjectId:   "ledger",
+			},
+		},
+	}
+}
+
+// ledgerFixtureUpdate records (or removes) the ledger entry for a single
+// fixture actually written by migration name, encoding the fixture itself
+// into the subject id so Rollback can recover exactly what was applied.
+func ledgerFixtureUpdate(op pb.RelationshipUpdate_Operation, name string, f Fixture) *pb.RelationshipUpdate {
+	return &pb.RelationshipUpdate{
+		Operation: op,
+		Relationship: &pb.Relationship{
+			Resource: &pb.ObjectReference{
+				ObjectType: ledgerResourceType,
+				ObjectId:   name,
+			},
+			Relation: "applied-fixture",
+			Subject: &pb.SubjectReference{
+				Object: &pb.ObjectReference{
+					ObjectType: ledgerResourceType,
+					ObjectId:   encodeFixture(f),
+				},
+			},
+		},
+	}
+}
+
+// encodeFixture and decodeFixture round-trip a Fixture through an object
+// id, which SpiceDB restricts to a narrower charset than JSON uses, hence
+// the hex encoding.
+func encodeFixture(f Fixture) string {
+	data, _ := json.Marshal(f)
+	return hex.EncodeToString(data)
+}
+
+func decodeFixture(encoded string) (Fixture, error) {
+	data, err := hex.DecodeString(encoded)
+	if err != nil {
+		return Fixture{}, err
+	}
+
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return Fixture{}, err
+	}
+
+	return f, nil
+}
@@ -0,0 +1,125 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadSchemaOrdersBySortedPath(t *testing.T) {
+	fsys := fstest.MapFS{
+		"b.zed": &fstest.MapFile{Data: []byte("definition b {}")},
+		"a.zed": &fstest.MapFile{Data: []byte("definition a {}")},
+	}
+
+	schema, err := loadSchema(fsys)
+	if err != nil {
+		t.Fatalf("loadSchema: %v", err)
+	}
+
+	wantOrder := []string{"definition a {}", "definition b {}"}
+	for i, want := range wantOrder {
+		if i == 0 {
+			if got := schema[:len(want)]; got != want {
+				t.Fatalf("schema should start with %q, got %q", want, schema)
+			}
+		}
+	}
+}
+
+func TestLoadFixturesOrdersBySortedPathAndConcatenates(t *testing.T) {
+	fsys := fstest.MapFS{
+		"02_second.yaml": &fstest.MapFile{Data: []byte(`
+relationships:
+  - resource: namespace/payment
+    resource_id: payment_2
+    relation: collector
+    subject: namespace/user
+    subject_id: john
+`)},
+		"01_first.yaml": &fstest.MapFile{Data: []byte(`
+relationships:
+  - resource: namespace/payment
+    resource_id: payment_1
+    relation: payer
+    subject: namespace/user
+    subject_id: bob
+`)},
+	}
+
+	fixtures, err := loadFixtures(fsys)
+	if err != nil {
+		t.Fatalf("loadFixtures: %v", err)
+	}
+
+	if len(fixtures) != 2 {
+		t.Fatalf("want 2 fixtures, got %d", len(fixtures))
+	}
+	if fixtures[0].SubjectID != "bob" || fixtures[1].SubjectID != "john" {
+		t.Fatalf("fixtures not loaded in sorted path order: %+v", fixtures)
+	}
+}
+
+func TestMergeSchemasReplacesMatchingBlocksAndPreservesOrder(t *testing.T) {
+	deployed := `definition namespace/user {}
+
+definition namespace/payment {
+	relation payer: namespace/user
+}
+`
+	incoming := `definition namespace/payment {
+	relation payer: namespace/user
+	relation collector: namespace/user
+}
+
+definition namespace/invoice {}
+`
+
+	merged := mergeSchemas(deployed, incoming)
+
+	blocks := splitSchemaBlocks(merged)
+	if len(blocks) != 3 {
+		t.Fatalf("want 3 blocks in merged schema, got %d: %v", len(blocks), blocks)
+	}
+
+	if blockHeader(blocks[0]) != "definition namespace/user" {
+		t.Fatalf("expected namespace/user first, got %q", blockHeader(blocks[0]))
+	}
+	if blockHeader(blocks[1]) != "definition namespace/payment" {
+		t.Fatalf("expected namespace/payment second, got %q", blockHeader(blocks[1]))
+	}
+	if !containsAll(blocks[1], "collector") {
+		t.Fatalf("expected updated namespace/payment block to include collector, got %q", blocks[1])
+	}
+	if blockHeader(blocks[2]) != "definition namespace/invoice" {
+		t.Fatalf("expected namespace/invoice appended last, got %q", blockHeader(blocks[2]))
+	}
+}
+
+func TestEncodeDecodeFixtureRoundTrips(t *testing.T) {
+	f := Fixture{
+		Resource:   "namespace/payment",
+		ResourceID: "payment_1",
+		Relation:   "collector",
+		Subject:    "namespace/user",
+		SubjectID:  "alice",
+	}
+
+	got, err := decodeFixture(encodeFixture(f))
+	if err != nil {
+		t.Fatalf("decodeFixture: %v", err)
+	}
+	if got != f {
+		t.Fatalf("round-tripped fixture = %+v, want %+v", got, f)
+	}
+}
+
+func containsAll(s, substr string) bool {
+	return len(s) >= len(substr) && (func() bool {
+		for i := 0; i+len(substr) <= len(s); i++ {
+			if s[i:i+len(substr)] == substr {
+				return true
+			}
+		}
+		return false
+	})()
+}